@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/progress"
+	"github.com/cecobask/imdb-trakt-sync/pkg/store"
+	"github.com/cecobask/imdb-trakt-sync/pkg/worker"
+	"go.uber.org/zap"
+)
+
+func main() {
+	var (
+		queueDbPath = flag.String("queue-db", "worker.db", "path to the sqlite job queue database")
+		storeDbPath = flag.String("store-db", "store.db", "path to the sqlite delta-sync store database")
+		listenAddr  = flag.String("listen", ":8000", "address the admin http api listens on")
+		silent      = flag.Bool("silent", false, "disable progress bars")
+		noProgress  = flag.Bool("no-progress", false, "alias for -silent")
+		fullResync  = flag.Bool("full-resync", false, "ignore the local store and resend every item to trakt")
+	)
+	flag.Parse()
+
+	syncProgress := progress.NewBarProgress()
+	if *silent || *noProgress {
+		syncProgress = progress.NewNoopProgress()
+	}
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("failure creating logger: %v", err)
+	}
+	defer logger.Sync()
+
+	queue, err := worker.NewQueue(*queueDbPath)
+	if err != nil {
+		logger.Fatal("failure creating job queue", zap.Error(err))
+	}
+	defer queue.Close()
+
+	syncStore, err := store.NewStore(*storeDbPath)
+	if err != nil {
+		logger.Fatal("failure creating sync store", zap.Error(err))
+	}
+	defer syncStore.Close()
+
+	imdbClient, err := client.NewImdbClient(client.ImdbConfig{
+		CookieAtMain:   os.Getenv("IMDB_COOKIE_AT_MAIN"),
+		CookieUbidMain: os.Getenv("IMDB_COOKIE_UBID_MAIN"),
+		DataSource:     os.Getenv("IMDB_DATA_SOURCE"),
+		OmdbApiKey:     os.Getenv("OMDB_API_KEY"),
+		Store:          syncStore,
+		FullResync:     *fullResync,
+		Progress:       syncProgress,
+	}, logger)
+	if err != nil {
+		logger.Fatal("failure creating imdb client", zap.Error(err))
+	}
+
+	traktClient, err := client.NewTraktClient(client.TraktConfig{
+		ClientId:     os.Getenv("TRAKT_CLIENT_ID"),
+		ClientSecret: os.Getenv("TRAKT_CLIENT_SECRET"),
+	}, logger)
+	if err != nil {
+		logger.Fatal("failure creating trakt client", zap.Error(err))
+	}
+
+	w := worker.NewWorker(queue, imdbClient, traktClient, logger)
+	stop := make(chan struct{})
+	workerDone := make(chan struct{})
+	go func() {
+		w.Run(stop)
+		close(workerDone)
+	}()
+
+	server := worker.NewServer(queue, logger)
+	httpServer := &http.Server{Addr: *listenAddr, Handler: server.Handler()}
+	go func() {
+		logger.Info("worker admin api listening", zap.String("addr", *listenAddr))
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("worker admin api failure", zap.Error(err))
+		}
+	}()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	<-sigCh
+	logger.Info("shutting down, waiting for the in-flight sync job to finish")
+	close(stop)
+	<-workerDone // let any in-flight trakt writes complete before we exit
+	_ = httpServer.Close()
+}
@@ -0,0 +1,19 @@
+// Package backoff computes jittered exponential retry delays shared by the
+// imdb transport and the worker queue.
+package backoff
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Jittered returns base*2^attempt, capped at max, plus up to half of that
+// delay as jitter.
+func Jittered(attempt int, base, max time.Duration) time.Duration {
+	delay := time.Duration(float64(base) * math.Pow(2, float64(attempt)))
+	if delay > max {
+		delay = max
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
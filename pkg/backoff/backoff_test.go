@@ -0,0 +1,36 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJittered_AtZeroDelayDoesNotPanic(t *testing.T) {
+	if d := Jittered(0, 0, time.Second); d < 0 {
+		t.Fatalf("expected a non-negative delay, got %v", d)
+	}
+}
+
+func TestJittered_CapsAtMaxPlusJitter(t *testing.T) {
+	base := time.Second
+	max := 2 * time.Second
+	for i := 0; i < 20; i++ {
+		d := Jittered(10, base, max) // attempt=10 pushes base*2^10 far past max
+		if d < max {
+			t.Fatalf("expected delay to be at least max, got %v", d)
+		}
+		if d > max+max/2+1 {
+			t.Fatalf("expected delay capped near max, got %v", d)
+		}
+	}
+}
+
+func TestJittered_GrowsWithAttempt(t *testing.T) {
+	base := 10 * time.Millisecond
+	max := time.Hour
+	small := Jittered(0, base, max)  // range [10ms, 15ms]
+	large := Jittered(4, base, max)  // range [160ms, 240ms]
+	if large <= small {
+		t.Fatalf("expected delay to grow with attempt, got small=%v large=%v", small, large)
+	}
+}
@@ -1,11 +1,18 @@
 package client
 
 import (
+	"bytes"
+	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"github.com/PuerkitoBio/goquery"
 	"github.com/cecobask/imdb-trakt-sync/pkg/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/progress"
+	"github.com/cecobask/imdb-trakt-sync/pkg/store"
 	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+	"io"
 	"mime"
 	"net/http"
 	"net/http/cookiejar"
@@ -13,6 +20,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -28,12 +36,18 @@ const (
 	imdbPathProfile       = "/profile"
 	imdbPathRatingsExport = "/user/%s/ratings/export"
 	imdbPathWatchlist     = "/watchlist"
+
+	progressPhaseLists = "lists"
 )
 
 type ImdbClient struct {
-	client *http.Client
-	config ImdbConfig
-	logger *zap.Logger
+	client      *http.Client
+	config      ImdbConfig
+	logger      *zap.Logger
+	dataSource  ImdbDataSource
+	lastRatings []entities.ImdbItem
+	lastListsMu sync.Mutex
+	lastLists   map[string]*entities.ImdbList
 }
 
 type ImdbConfig struct {
@@ -41,6 +55,26 @@ type ImdbConfig struct {
 	CookieUbidMain string
 	UserId         string
 	WatchlistId    string
+	// DataSource selects how ImdbClient backfills metadata once the csv
+	// export endpoints stop responding: "scrape" (default) or "omdb"
+	// ("hybrid" is accepted as an alias of "omdb").
+	DataSource string
+	OmdbApiKey string
+	// Concurrency bounds how many lists ListsGetAll fetches in parallel.
+	Concurrency int
+	// RequestTimeout bounds each individual http request.
+	RequestTimeout time.Duration
+	// RateLimit caps outgoing requests per second across the whole client.
+	RateLimit float64
+	// Store, when set, makes RatingsDelta/ListDelta report only what changed
+	// since the last sync; without it every item is reported as added.
+	Store *store.Store
+	// FullResync ignores Store when computing a delta, so every item is
+	// reported as added.
+	FullResync bool
+	// Progress reports sync phases to the caller. Defaults to a silent
+	// no-op progress.NewNoopProgress() when unset.
+	Progress progress.Progress
 }
 
 func NewImdbClient(config ImdbConfig, logger *zap.Logger) (ImdbClientInterface, error) {
@@ -48,12 +82,25 @@ func NewImdbClient(config ImdbConfig, logger *zap.Logger) (ImdbClientInterface,
 	if err != nil {
 		return nil, err
 	}
+	if config.Progress == nil {
+		config.Progress = progress.NewNoopProgress()
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaultImdbConcurrency
+	}
+	if !validDataSource(config.DataSource) {
+		logger.Error("unrecognised imdb data source, falling back to scrape", zap.String("dataSource", config.DataSource))
+	}
 	client := &ImdbClient{
 		client: &http.Client{
-			Jar: jar,
+			Jar:       jar,
+			Timeout:   requestTimeoutOrDefault(config.RequestTimeout),
+			Transport: newRetryRoundTripper(nil, config.RateLimit),
 		},
-		config: config,
-		logger: logger,
+		config:     config,
+		logger:     logger,
+		dataSource: buildDataSource(config),
+		lastLists:  make(map[string]*entities.ImdbList),
 	}
 	if err = client.hydrate(); err != nil {
 		return nil, fmt.Errorf("failure hydrating imdb client: %w", err)
@@ -96,7 +143,9 @@ func (c *ImdbClient) hydrate() error {
 }
 
 func (c *ImdbClient) doRequest(reqFields entities.RequestFields) (*http.Response, error) {
-	request, err := http.NewRequest(reqFields.Method, reqFields.Url, reqFields.Body)
+	ctx, cancel := withRequestDeadline(context.Background(), c.config.RequestTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, reqFields.Method, reqFields.Url, reqFields.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failure creating http request %s %s: %w", reqFields.Method, reqFields.Url, err)
 	}
@@ -104,6 +153,13 @@ func (c *ImdbClient) doRequest(reqFields entities.RequestFields) (*http.Response
 	if err != nil {
 		return nil, fmt.Errorf("failure sending http request %s %s: %w", reqFields.Method, reqFields.Url, err)
 	}
+	// buffer the body so it outlives the per-request deadline cancelled above
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failure reading http response body %s %s: %w", reqFields.Method, reqFields.Url, err)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
 	switch response.StatusCode {
 	case http.StatusOK:
 		break
@@ -140,7 +196,7 @@ func (c *ImdbClient) ListGet(listId string) (*entities.ImdbList, error) {
 	}
 	response, err := c.doRequest(requestFields)
 	if err != nil {
-		return nil, err
+		return c.listGetFallback(listId, err)
 	}
 	defer response.Body.Close()
 	if response.StatusCode == http.StatusNotFound {
@@ -152,7 +208,45 @@ func (c *ImdbClient) ListGet(listId string) (*entities.ImdbList, error) {
 			details:    fmt.Sprintf("list with id %s could not be found", listId),
 		}
 	}
-	return readImdbListResponse(response, listId)
+	list, err := readImdbListResponse(response, listId)
+	if err != nil {
+		return nil, err
+	}
+	c.lastListsMu.Lock()
+	c.lastLists[listId] = list
+	c.lastListsMu.Unlock()
+	return list, nil
+}
+
+// listGetFallback re-serves the last successfully fetched copy of a list,
+// refreshed via c.dataSource, when the csv export endpoint fails outright.
+func (c *ImdbClient) listGetFallback(listId string, originalErr error) (*entities.ImdbList, error) {
+	if !usesOmdbFallback(c.config.DataSource) {
+		return nil, originalErr
+	}
+	c.lastListsMu.Lock()
+	cached, ok := c.lastLists[listId]
+	c.lastListsMu.Unlock()
+	if !ok {
+		return nil, originalErr
+	}
+	c.logger.Error("imdb list export failed, serving cached copy enriched via omdb", zap.Error(originalErr), zap.String("listId", listId))
+	c.enrichItems(cached.ListItems)
+	return cached, nil
+}
+
+// enrichItems refreshes title type metadata for items via c.dataSource.
+func (c *ImdbClient) enrichItems(items []entities.ImdbItem) {
+	for i := range items {
+		metadata, err := c.dataSource.ItemMetadataGet(items[i].Id)
+		if err != nil {
+			c.logger.Error("failure enriching imdb item metadata", zap.Error(err), zap.String("imdbId", items[i].Id))
+			continue
+		}
+		if metadata.TitleType != "" {
+			items[i].TitleType = metadata.TitleType
+		}
+	}
 }
 
 func (c *ImdbClient) WatchlistGet() (*entities.ImdbList, error) {
@@ -182,21 +276,50 @@ func (c *ImdbClient) ListsGetAll() ([]entities.ImdbList, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failure creating goquery document from imdb response: %w", err)
 	}
-	var lists []entities.ImdbList
-	doc.Find(".user-list").Each(func(i int, selection *goquery.Selection) {
+	selections := doc.Find(".user-list")
+	var listIds []string
+	selections.Each(func(i int, selection *goquery.Selection) {
 		listId, ok := selection.Attr("id")
 		if !ok {
 			c.logger.Info("found no imdb lists")
 			return
 		}
-		list, err := c.ListGet(listId)
-		if err != nil {
-			c.logger.Error("unexpected error while scraping imdb lists", zap.Error(err))
-			return
-		}
-		list.TraktListSlug = buildTraktListName(list.ListName)
-		lists = append(lists, *list)
+		listIds = append(listIds, listId)
 	})
+
+	c.config.Progress.StartPhase(progressPhaseLists, len(listIds))
+	defer c.config.Progress.FinishPhase(progressPhaseLists)
+
+	var (
+		mu    sync.Mutex
+		lists []entities.ImdbList
+		errs  []error
+	)
+	group := new(errgroup.Group)
+	group.SetLimit(c.config.Concurrency)
+	for _, listId := range listIds {
+		listId := listId
+		group.Go(func() error {
+			list, err := c.ListGet(listId)
+			if err != nil {
+				c.logger.Error("unexpected error while fetching imdb list", zap.Error(err), zap.String("listId", listId))
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("list %s: %w", listId, err))
+				mu.Unlock()
+				return nil // keep fetching the remaining lists
+			}
+			list.TraktListSlug = buildTraktListName(list.ListName)
+			mu.Lock()
+			lists = append(lists, *list)
+			mu.Unlock()
+			c.config.Progress.Increment(progressPhaseLists)
+			return nil
+		})
+	}
+	_ = group.Wait()
+	if len(errs) > 0 {
+		return lists, fmt.Errorf("failure fetching %d of %d imdb lists: %w", len(errs), len(listIds), errors.Join(errs...))
+	}
 	return lists, nil
 }
 
@@ -251,10 +374,86 @@ func (c *ImdbClient) RatingsGet() ([]entities.ImdbItem, error) {
 	}
 	response, err := c.doRequest(requestFields)
 	if err != nil {
-		return nil, err
+		return c.ratingsGetFallback(err)
 	}
 	defer response.Body.Close()
-	return readImdbRatingsResponse(response)
+	ratings, err := readImdbRatingsResponse(response)
+	if err != nil {
+		return nil, err
+	}
+	c.lastRatings = ratings
+	return ratings, nil
+}
+
+// ratingsGetFallback mirrors listGetFallback for the ratings csv export.
+func (c *ImdbClient) ratingsGetFallback(originalErr error) ([]entities.ImdbItem, error) {
+	if !usesOmdbFallback(c.config.DataSource) {
+		return nil, originalErr
+	}
+	if c.lastRatings == nil {
+		return nil, originalErr
+	}
+	c.logger.Error("imdb ratings export failed, serving cached copy enriched via omdb", zap.Error(originalErr))
+	c.enrichItems(c.lastRatings)
+	return c.lastRatings, nil
+}
+
+// RatingsDelta fetches ratings and, when c.config.Store is set, reduces them
+// to just what changed since the last sync. With no store configured, or
+// with FullResync set, every item comes back as added.
+func (c *ImdbClient) RatingsDelta() (*store.Delta, error) {
+	ratings, err := c.RatingsGet()
+	if err != nil {
+		return nil, err
+	}
+	return c.storeDelta("", ratings)
+}
+
+// ListDelta is the list-scoped equivalent of RatingsDelta. When c.config.Store
+// is set it also records the list's name, trakt slug and a content
+// fingerprint, so ListEtag reflects what was last synced.
+func (c *ImdbClient) ListDelta(listId string) (*store.Delta, error) {
+	list, err := c.ListGet(listId)
+	if err != nil {
+		return nil, err
+	}
+	return c.ListDeltaFrom(list)
+}
+
+// ListDeltaFrom is the ListDelta equivalent for a list the caller already
+// fetched, e.g. via ListsGetAll, so it is not scraped from imdb a second time.
+func (c *ImdbClient) ListDeltaFrom(list *entities.ImdbList) (*store.Delta, error) {
+	delta, err := c.storeDelta(list.ListId, list.ListItems)
+	if err != nil {
+		return nil, err
+	}
+	if c.config.Store != nil {
+		if err = c.config.Store.UpsertList(list.ListId, list.ListName, list.TraktListSlug, store.ListFingerprint(list.ListItems)); err != nil {
+			return nil, fmt.Errorf("failure upserting list %s metadata: %w", list.ListId, err)
+		}
+	}
+	return delta, nil
+}
+
+func (c *ImdbClient) storeDelta(listId string, items []entities.ImdbItem) (*store.Delta, error) {
+	if c.config.Store == nil || c.config.FullResync {
+		return &store.Delta{Added: items}, nil
+	}
+	delta, err := c.config.Store.Diff(listId, items)
+	if err != nil {
+		return nil, fmt.Errorf("failure diffing %s against store: %w", deltaLabel(listId), err)
+	}
+	if err = c.config.Store.Snapshot(listId, items); err != nil {
+		return nil, fmt.Errorf("failure snapshotting %s to store: %w", deltaLabel(listId), err)
+	}
+	return delta, nil
+}
+
+func deltaLabel(listId string) string {
+	if listId == "" {
+		return "ratings"
+	}
+	return fmt.Sprintf("list %s", listId)
 }
 
 func readImdbListResponse(res *http.Response, listId string) (*entities.ImdbList, error) {
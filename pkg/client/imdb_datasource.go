@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const omdbRequestTimeout = 10 * time.Second
+
+const (
+	clientNameOmdb = "omdb"
+
+	imdbDataSourceScrape = "scrape"
+	imdbDataSourceOmdb   = "omdb"
+	imdbDataSourceHybrid = "hybrid" // alias of imdbDataSourceOmdb
+
+	omdbPathBase = "https://www.omdbapi.com/"
+)
+
+// usesOmdbFallback reports whether dataSource configures the omdb fallback
+// path. "hybrid" is accepted as an alias of "omdb" for callers still on the
+// originally documented three-value config.
+func usesOmdbFallback(dataSource string) bool {
+	return dataSource == imdbDataSourceOmdb || dataSource == imdbDataSourceHybrid
+}
+
+// validDataSource reports whether dataSource is empty or one of the known
+// values, so NewImdbClient can warn loudly instead of silently scraping.
+func validDataSource(dataSource string) bool {
+	switch dataSource {
+	case "", imdbDataSourceScrape, imdbDataSourceOmdb, imdbDataSourceHybrid:
+		return true
+	default:
+		return false
+	}
+}
+
+// ImdbDataSource hydrates supplementary metadata for a single imdb item by id.
+type ImdbDataSource interface {
+	ItemMetadataGet(imdbId string) (*ItemMetadata, error)
+}
+
+// ItemMetadata is the subset of title information ImdbClient can refresh
+// out-of-band from the csv export it normally relies on.
+type ItemMetadata struct {
+	TitleType string
+	Year      string
+	Genre     string
+}
+
+// ScrapeSource is the original data source: it has no independent way of
+// looking up a single title.
+type ScrapeSource struct{}
+
+func (s ScrapeSource) ItemMetadataGet(_ string) (*ItemMetadata, error) {
+	return nil, &ApiError{
+		clientName: clientNameImdb,
+		details:    "scrape source does not support looking up individual items",
+	}
+}
+
+// OmdbSource hydrates item metadata from omdbapi.com by imdb id.
+type OmdbSource struct {
+	apiKey string
+	client *http.Client
+}
+
+func NewOmdbSource(apiKey string) *OmdbSource {
+	return &OmdbSource{
+		apiKey: apiKey,
+		client: &http.Client{Timeout: omdbRequestTimeout},
+	}
+}
+
+type omdbResponse struct {
+	Type     string `json:"Type"`
+	Year     string `json:"Year"`
+	Genre    string `json:"Genre"`
+	Response string `json:"Response"`
+	Error    string `json:"Error"`
+}
+
+func (s *OmdbSource) ItemMetadataGet(imdbId string) (*ItemMetadata, error) {
+	if s.apiKey == "" {
+		return nil, &ApiError{
+			clientName: clientNameOmdb,
+			details:    "omdb api key is not configured",
+		}
+	}
+	query := url.Values{}
+	query.Set("i", imdbId)
+	query.Set("apikey", s.apiKey)
+	requestUrl := omdbPathBase + "?" + query.Encode()
+	ctx, cancel := context.WithTimeout(context.Background(), omdbRequestTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestUrl, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("failure creating http request %s %s: %w", http.MethodGet, requestUrl, err)
+	}
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("failure sending http request %s %s: %w", http.MethodGet, requestUrl, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, &ApiError{
+			clientName: clientNameOmdb,
+			httpMethod: request.Method,
+			url:        request.URL.String(),
+			StatusCode: response.StatusCode,
+			details:    fmt.Sprintf("unexpected status code %d", response.StatusCode),
+		}
+	}
+	var body omdbResponse
+	if err = json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failure decoding omdb response for %s: %w", imdbId, err)
+	}
+	if body.Response == "False" {
+		return nil, &ApiError{
+			clientName: clientNameOmdb,
+			httpMethod: request.Method,
+			url:        request.URL.String(),
+			details:    fmt.Sprintf("omdb could not resolve %s: %s", imdbId, body.Error),
+		}
+	}
+	return &ItemMetadata{
+		TitleType: body.Type,
+		Year:      body.Year,
+		Genre:     body.Genre,
+	}, nil
+}
+
+// buildDataSource picks the ImdbDataSource implementation matching
+// config.DataSource, defaulting to the scraper when unset.
+func buildDataSource(config ImdbConfig) ImdbDataSource {
+	if usesOmdbFallback(config.DataSource) {
+		return NewOmdbSource(config.OmdbApiKey)
+	}
+	return ScrapeSource{}
+}
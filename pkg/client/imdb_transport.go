@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/backoff"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultImdbConcurrency = 4
+	defaultImdbTimeout     = 30 * time.Second
+	defaultImdbRateLimit   = 5 // requests per second
+	defaultImdbRateBurst   = 5
+	retryMaxAttempts       = 4
+	retryBaseBackoff       = 250 * time.Millisecond
+	retryMaxBackoff        = 5 * time.Second
+)
+
+// retryRoundTripper wraps an http.RoundTripper with a shared token-bucket
+// rate limiter and jittered retries of transient 5xx/429 responses.
+type retryRoundTripper struct {
+	next    http.RoundTripper
+	limiter *rate.Limiter
+}
+
+func newRetryRoundTripper(next http.RoundTripper, requestsPerSecond float64) *retryRoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if requestsPerSecond <= 0 {
+		requestsPerSecond = defaultImdbRateLimit
+	}
+	return &retryRoundTripper{
+		next:    next,
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), defaultImdbRateBurst),
+	}
+}
+
+func (t *retryRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	var (
+		response *http.Response
+		err      error
+	)
+	for attempt := 0; attempt <= retryMaxAttempts; attempt++ {
+		if err = t.limiter.Wait(request.Context()); err != nil {
+			return nil, err
+		}
+		response, err = t.next.RoundTrip(cloneRequest(request))
+		if err == nil && !isRetryableStatus(response.StatusCode) {
+			return response, nil
+		}
+		if attempt == retryMaxAttempts {
+			break
+		}
+		if response != nil {
+			response.Body.Close()
+		}
+		select {
+		case <-time.After(backoff.Jittered(attempt, retryBaseBackoff, retryMaxBackoff)):
+		case <-request.Context().Done():
+			return nil, request.Context().Err()
+		}
+	}
+	return response, err
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+// cloneRequest returns a copy safe to retry: http.RoundTrippers must not
+// mutate or reuse the original request's body across attempts, so the body
+// is reopened through GetBody. A request without GetBody keeps sharing its
+// original Body.
+func cloneRequest(request *http.Request) *http.Request {
+	clone := request.Clone(request.Context())
+	if request.GetBody != nil {
+		if body, err := request.GetBody(); err == nil {
+			clone.Body = body
+		}
+	}
+	return clone
+}
+
+// withRequestDeadline gives a single outgoing request its own bounded
+// context, independent of the client-wide timeout.
+func withRequestDeadline(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, requestTimeoutOrDefault(timeout))
+}
+
+func requestTimeoutOrDefault(timeout time.Duration) time.Duration {
+	if timeout <= 0 {
+		return defaultImdbTimeout
+	}
+	return timeout
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestIsRetryableStatus(t *testing.T) {
+	cases := map[int]bool{
+		http.StatusOK:                  false,
+		http.StatusNotFound:            false,
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusServiceUnavailable:  true,
+	}
+	for status, want := range cases {
+		if got := isRetryableStatus(status); got != want {
+			t.Fatalf("isRetryableStatus(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestRetryRoundTripper_RetriesTransientStatusThenSucceeds(t *testing.T) {
+	var attempts int
+	next := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		status := http.StatusServiceUnavailable
+		if attempts == 2 {
+			status = http.StatusOK
+		}
+		return &http.Response{StatusCode: status, Body: http.NoBody, Request: r}, nil
+	})
+	rt := newRetryRoundTripper(next, 1000) // high rate so the limiter never blocks the test
+	req, err := http.NewRequest(http.MethodGet, "http://example.com", http.NoBody)
+	if err != nil {
+		t.Fatalf("unexpected error building request: %v", err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected eventual 200, got %d", resp.StatusCode)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+}
+
+func TestRetryRoundTripper_DoesNotRetryNonTransientStatus(t *testing.T) {
+	var attempts int
+	next := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		attempts++
+		return &http.Response{StatusCode: http.StatusNotFound, Body: http.NoBody, Request: r}, nil
+	})
+	rt := newRetryRoundTripper(next, 1000)
+	req, err := http.NewRequest(http.MethodGet, "http://example.com", http.NoBody)
+	if err != nil {
+		t.Fatalf("unexpected error building request: %v", err)
+	}
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected the 404 to be returned as-is, got %d", resp.StatusCode)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected a single attempt for a non-retryable status, got %d", attempts)
+	}
+}
@@ -0,0 +1,111 @@
+package progress
+
+import (
+	"fmt"
+
+	"github.com/cheggaaa/pb/v3"
+)
+
+// EventKind categorises an Event so a subscriber can filter without string
+// matching on Phase.
+type EventKind string
+
+const (
+	EventKindPhaseStarted  EventKind = "phase_started"
+	EventKindItemCompleted EventKind = "item_completed"
+	EventKindRetry         EventKind = "retry"
+	EventKindPhaseFinished EventKind = "phase_finished"
+)
+
+// Event is a single progress update. Total is only meaningful on
+// EventKindPhaseStarted.
+type Event struct {
+	Kind    EventKind
+	Phase   string
+	Current int
+	Total   int
+}
+
+// Progress reports per-phase progress of a sync, both as a terminal bar and
+// on a channel.
+type Progress interface {
+	StartPhase(phase string, total int)
+	Increment(phase string)
+	Retry(phase string)
+	FinishPhase(phase string)
+	Events() <-chan Event
+	Close()
+}
+
+// NewBarProgress renders github.com/cheggaaa/pb/v3 bars per phase and
+// mirrors each update onto an events channel buffered to 64; updates that
+// arrive while the buffer is full are dropped from the channel.
+func NewBarProgress() Progress {
+	return &barProgress{
+		bars:   make(map[string]*pb.ProgressBar),
+		events: make(chan Event, 64),
+	}
+}
+
+type barProgress struct {
+	bars   map[string]*pb.ProgressBar
+	events chan Event
+}
+
+func (p *barProgress) StartPhase(phase string, total int) {
+	bar := pb.StartNew(total)
+	bar.Set("prefix", fmt.Sprintf("%-24s", phase))
+	p.bars[phase] = bar
+	p.emit(Event{Kind: EventKindPhaseStarted, Phase: phase, Total: total})
+}
+
+func (p *barProgress) Increment(phase string) {
+	if bar, ok := p.bars[phase]; ok {
+		bar.Increment()
+	}
+	p.emit(Event{Kind: EventKindItemCompleted, Phase: phase})
+}
+
+func (p *barProgress) Retry(phase string) {
+	p.emit(Event{Kind: EventKindRetry, Phase: phase})
+}
+
+func (p *barProgress) FinishPhase(phase string) {
+	if bar, ok := p.bars[phase]; ok {
+		bar.Finish()
+		delete(p.bars, phase)
+	}
+	p.emit(Event{Kind: EventKindPhaseFinished, Phase: phase})
+}
+
+func (p *barProgress) Events() <-chan Event {
+	return p.events
+}
+
+func (p *barProgress) Close() {
+	close(p.events)
+}
+
+func (p *barProgress) emit(e Event) {
+	select {
+	case p.events <- e:
+	default: // nobody draining the channel yet; the bars remain the source of truth
+	}
+}
+
+// NewNoopProgress draws no bars, for --silent/--no-progress. The events
+// channel stays open so callers can always range over Events() unconditionally.
+func NewNoopProgress() Progress {
+	return &noopProgress{events: make(chan Event)}
+}
+
+type noopProgress struct {
+	events chan Event
+}
+
+func (p *noopProgress) StartPhase(string, int) {}
+func (p *noopProgress) Increment(string)       {}
+func (p *noopProgress) Retry(string)           {}
+func (p *noopProgress) FinishPhase(string)     {}
+func (p *noopProgress) Events() <-chan Event   { return p.events }
+func (p *noopProgress) Close()                 { close(p.events) }
@@ -0,0 +1,47 @@
+package progress
+
+import "testing"
+
+func TestNoopProgress_EventsChannelStaysEmptyAndClosesOnClose(t *testing.T) {
+	p := NewNoopProgress()
+	p.StartPhase("lists", 10)
+	p.Increment("lists")
+	p.Retry("lists")
+	p.FinishPhase("lists")
+	p.Close()
+	if _, ok := <-p.Events(); ok {
+		t.Fatalf("expected the noop progress channel to be closed empty")
+	}
+}
+
+func TestBarProgress_EmitsEventsInOrder(t *testing.T) {
+	p := NewBarProgress()
+	p.StartPhase("lists", 2)
+	p.Increment("lists")
+	p.Retry("lists")
+	p.FinishPhase("lists")
+	p.Close()
+
+	var kinds []EventKind
+	for e := range p.Events() {
+		kinds = append(kinds, e.Kind)
+	}
+	want := []EventKind{EventKindPhaseStarted, EventKindItemCompleted, EventKindRetry, EventKindPhaseFinished}
+	if len(kinds) != len(want) {
+		t.Fatalf("expected %d events, got %d: %+v", len(want), len(kinds), kinds)
+	}
+	for i, k := range want {
+		if kinds[i] != k {
+			t.Fatalf("event %d: expected %s, got %s", i, k, kinds[i])
+		}
+	}
+}
+
+func TestBarProgress_FinishPhaseForgetsTheBar(t *testing.T) {
+	p := NewBarProgress().(*barProgress)
+	p.StartPhase("lists", 1)
+	p.FinishPhase("lists")
+	if _, ok := p.bars["lists"]; ok {
+		t.Fatalf("expected FinishPhase to remove the bar from tracking")
+	}
+}
@@ -0,0 +1,117 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/entities"
+)
+
+// Delta is the result of comparing a freshly fetched snapshot of a list (or
+// the ratings pseudo-list) against what was last persisted: only Added,
+// Removed and Changed need to reach the trakt client.
+type Delta struct {
+	Added   []entities.ImdbItem
+	Removed []entities.ImdbItem
+	Changed []entities.ImdbItem
+}
+
+func checksum(item entities.ImdbItem) string {
+	rating := ""
+	if item.Rating != nil {
+		rating = fmt.Sprintf("%d", *item.Rating)
+	}
+	ratingDate := ""
+	if item.RatingDate != nil {
+		ratingDate = item.RatingDate.Format(time.RFC3339)
+	}
+	sum := sha256.Sum256([]byte(item.TitleType + "|" + rating + "|" + ratingDate))
+	return hex.EncodeToString(sum[:])
+}
+
+// Diff compares items against whatever was stored for listId (use
+// listId == "" for the ratings pseudo-list) and returns what changed,
+// without persisting anything.
+func (s *Store) Diff(listId string, items []entities.ImdbItem) (*Delta, error) {
+	rows, err := s.db.Query(`SELECT imdb_id, checksum FROM items WHERE list_id = ?`, listId)
+	if err != nil {
+		return nil, fmt.Errorf("failure reading stored items for list %s: %w", listId, err)
+	}
+	defer rows.Close()
+	stored := make(map[string]string)
+	for rows.Next() {
+		var imdbId, sum string
+		if err = rows.Scan(&imdbId, &sum); err != nil {
+			return nil, fmt.Errorf("failure scanning stored item for list %s: %w", listId, err)
+		}
+		stored[imdbId] = sum
+	}
+
+	seen := make(map[string]bool, len(items))
+	delta := &Delta{}
+	for _, item := range items {
+		seen[item.Id] = true
+		sum := checksum(item)
+		previous, existed := stored[item.Id]
+		switch {
+		case !existed:
+			delta.Added = append(delta.Added, item)
+		case previous != sum:
+			delta.Changed = append(delta.Changed, item)
+		}
+	}
+	for imdbId := range stored {
+		if !seen[imdbId] {
+			delta.Removed = append(delta.Removed, entities.ImdbItem{Id: imdbId})
+		}
+	}
+	return delta, nil
+}
+
+// ListFingerprint hashes every item's id and checksum together into a single
+// value, so the etag stored by UpsertList changes whenever a list's contents
+// do, without re-diffing it.
+func ListFingerprint(items []entities.ImdbItem) string {
+	h := sha256.New()
+	for _, item := range items {
+		h.Write([]byte(item.Id))
+		h.Write([]byte(checksum(item)))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Snapshot overwrites the persisted items for listId with items, so the
+// next Diff call is computed against this run's results.
+func (s *Store) Snapshot(listId string, items []entities.ImdbItem) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failure starting snapshot transaction for list %s: %w", listId, err)
+	}
+	defer tx.Rollback()
+	if _, err = tx.Exec(`DELETE FROM items WHERE list_id = ?`, listId); err != nil {
+		return fmt.Errorf("failure clearing stored items for list %s: %w", listId, err)
+	}
+	now := time.Now()
+	for _, item := range items {
+		var rating any
+		if item.Rating != nil {
+			rating = *item.Rating
+		}
+		var ratingDate any
+		if item.RatingDate != nil {
+			ratingDate = *item.RatingDate
+		}
+		if _, err = tx.Exec(
+			`INSERT INTO items (imdb_id, title_type, rating, rating_date, list_id, checksum, fetched_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			item.Id, item.TitleType, rating, ratingDate, listId, checksum(item), now,
+		); err != nil {
+			return fmt.Errorf("failure persisting item %s for list %s: %w", item.Id, listId, err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failure committing snapshot for list %s: %w", listId, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/entities"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := NewStore(":memory:")
+	if err != nil {
+		t.Fatalf("failure creating test store: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+func TestDiff_FirstRunAddsEverything(t *testing.T) {
+	s := newTestStore(t)
+	items := []entities.ImdbItem{{Id: "tt1"}, {Id: "tt2"}}
+	delta, err := s.Diff("", items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(delta.Added) != 2 || len(delta.Removed) != 0 || len(delta.Changed) != 0 {
+		t.Fatalf("expected both items added, got %+v", delta)
+	}
+}
+
+func TestDiff_DetectsAddedRemovedAndChanged(t *testing.T) {
+	s := newTestStore(t)
+	initial := []entities.ImdbItem{{Id: "tt1", TitleType: "movie"}, {Id: "tt2", TitleType: "movie"}}
+	if err := s.Snapshot("", initial); err != nil {
+		t.Fatalf("unexpected error snapshotting: %v", err)
+	}
+	next := []entities.ImdbItem{{Id: "tt2", TitleType: "tvSeries"}, {Id: "tt3", TitleType: "movie"}}
+	delta, err := s.Diff("", next)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(delta.Added) != 1 || delta.Added[0].Id != "tt3" {
+		t.Fatalf("expected tt3 added, got %+v", delta.Added)
+	}
+	if len(delta.Removed) != 1 || delta.Removed[0].Id != "tt1" {
+		t.Fatalf("expected tt1 removed, got %+v", delta.Removed)
+	}
+	if len(delta.Changed) != 1 || delta.Changed[0].Id != "tt2" {
+		t.Fatalf("expected tt2 changed, got %+v", delta.Changed)
+	}
+}
+
+func TestDiff_AgainstIdenticalSnapshotReportsNothing(t *testing.T) {
+	s := newTestStore(t)
+	items := []entities.ImdbItem{{Id: "tt1"}}
+	if err := s.Snapshot("", items); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	delta, err := s.Diff("", items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(delta.Added)+len(delta.Removed)+len(delta.Changed) != 0 {
+		t.Fatalf("expected no delta against identical snapshot, got %+v", delta)
+	}
+}
+
+func TestDiff_IsScopedPerList(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.Snapshot("ls1", []entities.ImdbItem{{Id: "tt1"}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	delta, err := s.Diff("ls2", []entities.ImdbItem{{Id: "tt1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(delta.Added) != 1 {
+		t.Fatalf("expected item to be new to a different list, got %+v", delta)
+	}
+}
+
+func TestListFingerprint_ChangesWithItems(t *testing.T) {
+	a := []entities.ImdbItem{{Id: "tt1", TitleType: "movie"}}
+	b := []entities.ImdbItem{{Id: "tt1", TitleType: "tvSeries"}}
+	if ListFingerprint(a) == ListFingerprint(b) {
+		t.Fatalf("expected fingerprint to change when item metadata changes")
+	}
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ListEtag returns the last stored etag for a list, or "" if UpsertList has
+// never recorded it.
+func (s *Store) ListEtag(listId string) (string, error) {
+	var etag string
+	err := s.db.QueryRow(`SELECT etag FROM lists WHERE id = ?`, listId).Scan(&etag)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failure reading etag for list %s: %w", listId, err)
+	}
+	return etag, nil
+}
+
+// UpsertList records (or updates) the name, trakt slug and etag of a list.
+func (s *Store) UpsertList(listId, name, traktSlug, etag string) error {
+	_, err := s.db.Exec(
+		`INSERT INTO lists (id, name, trakt_slug, etag) VALUES (?, ?, ?, ?)
+		 ON CONFLICT(id) DO UPDATE SET name = excluded.name, trakt_slug = excluded.trakt_slug, etag = excluded.etag`,
+		listId, name, traktSlug, etag,
+	)
+	if err != nil {
+		return fmt.Errorf("failure upserting list %s: %w", listId, err)
+	}
+	return nil
+}
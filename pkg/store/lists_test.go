@@ -0,0 +1,38 @@
+package store
+
+import "testing"
+
+func TestUpsertList_SetsAndUpdatesEtag(t *testing.T) {
+	s := newTestStore(t)
+	if err := s.UpsertList("ls1", "Favourites", "favourites", "etag-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	etag, err := s.ListEtag("ls1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if etag != "etag-1" {
+		t.Fatalf("expected etag-1, got %q", etag)
+	}
+	if err = s.UpsertList("ls1", "Favourites", "favourites", "etag-2"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	etag, err = s.ListEtag("ls1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if etag != "etag-2" {
+		t.Fatalf("expected etag-2 after update, got %q", etag)
+	}
+}
+
+func TestListEtag_UnknownListReturnsEmpty(t *testing.T) {
+	s := newTestStore(t)
+	etag, err := s.ListEtag("missing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if etag != "" {
+		t.Fatalf("expected empty etag for unknown list, got %q", etag)
+	}
+}
@@ -0,0 +1,71 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// migration mirrors the numbered, never-edited-after-merge migrations used
+// in projects like Gitea's models/migrations: each one is a single forward
+// step, applied in id order, and recorded so it never reruns.
+type migration struct {
+	id  int
+	sql string
+}
+
+var migrations = []migration{
+	{
+		id: 1,
+		sql: `
+CREATE TABLE IF NOT EXISTS items (
+	imdb_id      TEXT NOT NULL,
+	title_type   TEXT NOT NULL DEFAULT '',
+	rating       INTEGER,
+	rating_date  DATETIME,
+	list_id      TEXT NOT NULL DEFAULT '',
+	checksum     TEXT NOT NULL,
+	fetched_at   DATETIME NOT NULL,
+	PRIMARY KEY (imdb_id, list_id)
+);
+CREATE TABLE IF NOT EXISTS lists (
+	id         TEXT PRIMARY KEY,
+	name       TEXT NOT NULL,
+	trakt_slug TEXT NOT NULL DEFAULT '',
+	etag       TEXT NOT NULL DEFAULT ''
+);
+`,
+	},
+}
+
+// Migrate applies every migration newer than the store's current schema
+// version, in order, inside its own transaction.
+func Migrate(db *sql.DB) error {
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (id INTEGER PRIMARY KEY, applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP)`); err != nil {
+		return fmt.Errorf("failure creating schema_migrations table: %w", err)
+	}
+	for _, m := range migrations {
+		var applied int
+		if err := db.QueryRow(`SELECT COUNT(1) FROM schema_migrations WHERE id = ?`, m.id).Scan(&applied); err != nil {
+			return fmt.Errorf("failure checking migration %d: %w", m.id, err)
+		}
+		if applied > 0 {
+			continue
+		}
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failure starting migration %d: %w", m.id, err)
+		}
+		if _, err = tx.Exec(m.sql); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failure applying migration %d: %w", m.id, err)
+		}
+		if _, err = tx.Exec(`INSERT INTO schema_migrations (id) VALUES (?)`, m.id); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failure recording migration %d: %w", m.id, err)
+		}
+		if err = tx.Commit(); err != nil {
+			return fmt.Errorf("failure committing migration %d: %w", m.id, err)
+		}
+	}
+	return nil
+}
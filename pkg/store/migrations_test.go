@@ -0,0 +1,17 @@
+package store
+
+import "testing"
+
+func TestMigrate_IsIdempotent(t *testing.T) {
+	s := newTestStore(t)
+	if err := Migrate(s.db); err != nil {
+		t.Fatalf("expected re-running migrations to be a no-op, got: %v", err)
+	}
+	var count int
+	if err := s.db.QueryRow(`SELECT COUNT(1) FROM schema_migrations`).Scan(&count); err != nil {
+		t.Fatalf("unexpected error reading schema_migrations: %v", err)
+	}
+	if count != len(migrations) {
+		t.Fatalf("expected %d recorded migrations, got %d", len(migrations), count)
+	}
+}
@@ -0,0 +1,31 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Store is a local, on-disk snapshot of the imdb items and lists that have
+// already been synced to trakt. ImdbClient reads through it to compute
+// deltas before making any trakt api calls, and writes through it once a
+// sync completes, so re-running after a crash only resends what changed.
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(dataSourceName string) (*Store, error) {
+	db, err := sql.Open("sqlite3", dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failure opening store database %s: %w", dataSourceName, err)
+	}
+	if err = Migrate(db); err != nil {
+		return nil, fmt.Errorf("failure migrating store database %s: %w", dataSourceName, err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
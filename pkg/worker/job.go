@@ -0,0 +1,46 @@
+package worker
+
+import "time"
+
+// Kind identifies what a SyncJob should do once leased.
+type Kind string
+
+const (
+	KindFull      Kind = "full"
+	KindRatings   Kind = "ratings"
+	KindWatchlist Kind = "watchlist"
+	KindList      Kind = "list"
+)
+
+// Valid reports whether k is one of the known Kind values.
+func (k Kind) Valid() bool {
+	switch k {
+	case KindFull, KindRatings, KindWatchlist, KindList:
+		return true
+	default:
+		return false
+	}
+}
+
+// Status tracks a SyncJob through the queue.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusLeased  Status = "leased"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+)
+
+// SyncJob is a single unit of work the worker leases and executes. ListId is
+// only read when Kind is KindList.
+type SyncJob struct {
+	ID          int64
+	UserID      string
+	Kind        Kind
+	ListId      string
+	ScheduledAt time.Time
+	Status      Status
+	LastError   string
+	Attempts    int
+}
@@ -0,0 +1,136 @@
+package worker
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const queueSchema = `
+CREATE TABLE IF NOT EXISTS sync_jobs (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id      TEXT NOT NULL,
+	kind         TEXT NOT NULL,
+	list_id      TEXT NOT NULL DEFAULT '',
+	scheduled_at DATETIME NOT NULL,
+	status       TEXT NOT NULL DEFAULT 'pending',
+	last_error   TEXT NOT NULL DEFAULT '',
+	attempts     INTEGER NOT NULL DEFAULT 0
+);
+`
+
+// Queue is a SQLite backed persistent store of SyncJob.
+type Queue struct {
+	db *sql.DB
+}
+
+func NewQueue(dataSourceName string) (*Queue, error) {
+	db, err := sql.Open("sqlite3", dataSourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failure opening job queue database %s: %w", dataSourceName, err)
+	}
+	if _, err = db.Exec(queueSchema); err != nil {
+		return nil, fmt.Errorf("failure applying job queue schema: %w", err)
+	}
+	return &Queue{db: db}, nil
+}
+
+func (q *Queue) Close() error {
+	return q.db.Close()
+}
+
+// Enqueue persists a new job and returns it with its assigned ID.
+func (q *Queue) Enqueue(job SyncJob) (*SyncJob, error) {
+	if job.ScheduledAt.IsZero() {
+		job.ScheduledAt = time.Now()
+	}
+	result, err := q.db.Exec(
+		`INSERT INTO sync_jobs (user_id, kind, list_id, scheduled_at, status) VALUES (?, ?, ?, ?, ?)`,
+		job.UserID, job.Kind, job.ListId, job.ScheduledAt, StatusPending,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failure enqueueing sync job for user %s: %w", job.UserID, err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failure reading sync job id: %w", err)
+	}
+	job.ID = id
+	job.Status = StatusPending
+	return &job, nil
+}
+
+// Get fetches a single job by ID.
+func (q *Queue) Get(id int64) (*SyncJob, error) {
+	row := q.db.QueryRow(
+		`SELECT id, user_id, kind, list_id, scheduled_at, status, last_error, attempts FROM sync_jobs WHERE id = ?`,
+		id,
+	)
+	var job SyncJob
+	if err := row.Scan(&job.ID, &job.UserID, &job.Kind, &job.ListId, &job.ScheduledAt, &job.Status, &job.LastError, &job.Attempts); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("sync job %d not found", id)
+		}
+		return nil, fmt.Errorf("failure reading sync job %d: %w", id, err)
+	}
+	return &job, nil
+}
+
+// Lease atomically claims the oldest pending job that is due, marking it
+// StatusLeased.
+func (q *Queue) Lease() (*SyncJob, error) {
+	tx, err := q.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failure starting lease transaction: %w", err)
+	}
+	defer tx.Rollback()
+	row := tx.QueryRow(
+		`SELECT id, user_id, kind, list_id, scheduled_at, status, last_error, attempts
+		 FROM sync_jobs WHERE status = ? AND scheduled_at <= ? ORDER BY scheduled_at LIMIT 1`,
+		StatusPending, time.Now(),
+	)
+	var job SyncJob
+	if err = row.Scan(&job.ID, &job.UserID, &job.Kind, &job.ListId, &job.ScheduledAt, &job.Status, &job.LastError, &job.Attempts); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failure leasing sync job: %w", err)
+	}
+	if _, err = tx.Exec(`UPDATE sync_jobs SET status = ? WHERE id = ?`, StatusLeased, job.ID); err != nil {
+		return nil, fmt.Errorf("failure marking sync job %d leased: %w", job.ID, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failure committing lease of sync job %d: %w", job.ID, err)
+	}
+	job.Status = StatusLeased
+	return &job, nil
+}
+
+// Complete marks a job done.
+func (q *Queue) Complete(id int64) error {
+	if _, err := q.db.Exec(`UPDATE sync_jobs SET status = ?, last_error = '' WHERE id = ?`, StatusDone, id); err != nil {
+		return fmt.Errorf("failure completing sync job %d: %w", id, err)
+	}
+	return nil
+}
+
+// Fail records an attempt failure. If retryAt is non-zero the job goes back
+// to StatusPending to be retried later, otherwise it is parked as
+// StatusFailed for operator inspection.
+func (q *Queue) Fail(id int64, cause error, retryAt time.Time) error {
+	status := StatusFailed
+	scheduledAtClause := ""
+	args := []any{status, cause.Error(), id}
+	if !retryAt.IsZero() {
+		status = StatusPending
+		scheduledAtClause = ", scheduled_at = ?"
+		args = []any{status, cause.Error(), retryAt, id}
+	}
+	query := fmt.Sprintf(`UPDATE sync_jobs SET status = ?, last_error = ?, attempts = attempts + 1%s WHERE id = ?`, scheduledAtClause)
+	if _, err := q.db.Exec(query, args...); err != nil {
+		return fmt.Errorf("failure recording failure of sync job %d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// Server exposes a minimal admin API over a Queue: POST /jobs to enqueue,
+// GET /jobs/{id} to inspect.
+type Server struct {
+	queue  *Queue
+	logger *zap.Logger
+}
+
+func NewServer(queue *Queue, logger *zap.Logger) *Server {
+	return &Server{queue: queue, logger: logger}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/jobs", s.handleJobs)
+	mux.HandleFunc("/jobs/", s.handleJob)
+	return mux
+}
+
+type enqueueRequest struct {
+	UserID string `json:"userId"`
+	Kind   Kind   `json:"kind"`
+	ListId string `json:"listId,omitempty"`
+}
+
+func (s *Server) handleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req enqueueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !req.Kind.Valid() {
+		http.Error(w, fmt.Sprintf("invalid job kind %q", req.Kind), http.StatusBadRequest)
+		return
+	}
+	job, err := s.queue.Enqueue(SyncJob{UserID: req.UserID, Kind: req.Kind, ListId: req.ListId})
+	if err != nil {
+		s.logger.Error("failure enqueueing sync job", zap.Error(err))
+		http.Error(w, "failure enqueueing sync job", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	idStr := strings.TrimPrefix(r.URL.Path, "/jobs/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		http.Error(w, "invalid job id", http.StatusBadRequest)
+		return
+	}
+	job, err := s.queue.Get(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(job)
+}
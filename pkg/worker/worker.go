@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/backoff"
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/store"
+	"go.uber.org/zap"
+)
+
+const (
+	maxAttempts  = 5
+	baseBackoff  = 2 * time.Second
+	maxBackoff   = 5 * time.Minute
+	leasePollGap = time.Second
+)
+
+// Worker leases SyncJob entries from a Queue and drives them to completion
+// against the imdb and trakt clients, retrying transient ApiError failures
+// with jittered exponential backoff.
+type Worker struct {
+	queue  *Queue
+	imdb   client.ImdbClientInterface
+	trakt  client.TraktClientInterface
+	logger *zap.Logger
+}
+
+func NewWorker(queue *Queue, imdb client.ImdbClientInterface, trakt client.TraktClientInterface, logger *zap.Logger) *Worker {
+	return &Worker{
+		queue:  queue,
+		imdb:   imdb,
+		trakt:  trakt,
+		logger: logger,
+	}
+}
+
+// Run blocks, leasing and executing jobs until stop is closed.
+func (w *Worker) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(leasePollGap)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			job, err := w.queue.Lease()
+			if err != nil {
+				w.logger.Error("failure leasing sync job", zap.Error(err))
+				continue
+			}
+			if job == nil {
+				continue
+			}
+			w.process(job)
+		}
+	}
+}
+
+func (w *Worker) process(job *SyncJob) {
+	err := w.execute(job)
+	if err == nil {
+		if cerr := w.queue.Complete(job.ID); cerr != nil {
+			w.logger.Error("failure marking sync job complete", zap.Error(cerr), zap.Int64("jobId", job.ID))
+		}
+		return
+	}
+	var retryAt time.Time
+	if job.Attempts+1 < maxAttempts && isRetryable(err) {
+		retryAt = time.Now().Add(backoff.Jittered(job.Attempts, baseBackoff, maxBackoff))
+	}
+	if ferr := w.queue.Fail(job.ID, err, retryAt); ferr != nil {
+		w.logger.Error("failure recording sync job failure", zap.Error(ferr), zap.Int64("jobId", job.ID))
+	}
+}
+
+func (w *Worker) execute(job *SyncJob) error {
+	switch job.Kind {
+	case KindRatings:
+		return w.syncRatings()
+	case KindWatchlist:
+		list, err := w.imdb.WatchlistGet()
+		if err != nil {
+			return err
+		}
+		if len(list.ListItems) == 0 {
+			return nil
+		}
+		return w.trakt.WatchlistItemsAdd(list.ListItems)
+	case KindList:
+		list, err := w.imdb.ListGet(job.ListId)
+		if err != nil {
+			return err
+		}
+		return w.syncList(list)
+	case KindFull:
+		if err := w.syncRatings(); err != nil {
+			return err
+		}
+		lists, listsErr := w.imdb.ListsGetAll()
+		var errs []error
+		for i := range lists {
+			if err := w.syncList(&lists[i]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if listsErr != nil {
+			errs = append(errs, listsErr)
+		}
+		return errors.Join(errs...)
+	default:
+		return fmt.Errorf("unknown sync job kind %q", job.Kind)
+	}
+}
+
+func (w *Worker) syncRatings() error {
+	delta, err := w.imdb.RatingsDelta()
+	if err != nil {
+		return err
+	}
+	return w.pushDelta(delta, w.trakt.RatingsAdd, w.trakt.RatingsRemove)
+}
+
+func (w *Worker) syncList(list *entities.ImdbList) error {
+	delta, err := w.imdb.ListDeltaFrom(list)
+	if err != nil {
+		return err
+	}
+	listId := list.ListId
+	return w.pushDelta(delta,
+		func(items []entities.ImdbItem) error { return w.trakt.ListItemsAdd(listId, items) },
+		func(items []entities.ImdbItem) error { return w.trakt.ListItemsRemove(listId, items) },
+	)
+}
+
+// pushDelta sends delta.Added/Changed to add and delta.Removed to remove,
+// skipping either call when there is nothing to send.
+func (w *Worker) pushDelta(delta *store.Delta, add, remove func([]entities.ImdbItem) error) error {
+	if additions := append(delta.Added, delta.Changed...); len(additions) > 0 {
+		if err := add(additions); err != nil {
+			return err
+		}
+	}
+	if len(delta.Removed) > 0 {
+		if err := remove(delta.Removed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isRetryable(err error) bool {
+	var apiErr *client.ApiError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == 429
+	}
+	return true
+}
+
@@ -0,0 +1,145 @@
+package worker
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cecobask/imdb-trakt-sync/pkg/client"
+	"github.com/cecobask/imdb-trakt-sync/pkg/entities"
+	"github.com/cecobask/imdb-trakt-sync/pkg/store"
+	"go.uber.org/zap"
+)
+
+type fakeImdb struct {
+	watchlist    *entities.ImdbList
+	list         *entities.ImdbList
+	lists        []entities.ImdbList
+	listsErr     error
+	ratingsDelta *store.Delta
+	listDelta    *store.Delta
+}
+
+func (f *fakeImdb) RatingsGet() ([]entities.ImdbItem, error)   { return nil, nil }
+func (f *fakeImdb) WatchlistGet() (*entities.ImdbList, error)  { return f.watchlist, nil }
+func (f *fakeImdb) ListGet(string) (*entities.ImdbList, error) { return f.list, nil }
+func (f *fakeImdb) ListsGetAll() ([]entities.ImdbList, error)  { return f.lists, f.listsErr }
+func (f *fakeImdb) RatingsDelta() (*store.Delta, error)        { return f.ratingsDelta, nil }
+func (f *fakeImdb) ListDelta(string) (*store.Delta, error)     { return f.listDelta, nil }
+func (f *fakeImdb) ListDeltaFrom(*entities.ImdbList) (*store.Delta, error) {
+	return f.listDelta, nil
+}
+
+type fakeTrakt struct {
+	ratingsAdded   []entities.ImdbItem
+	ratingsRemoved []entities.ImdbItem
+	watchlistAdded []entities.ImdbItem
+	listAdds       map[string][]entities.ImdbItem
+}
+
+func newFakeTrakt() *fakeTrakt {
+	return &fakeTrakt{listAdds: make(map[string][]entities.ImdbItem)}
+}
+
+func (f *fakeTrakt) RatingsAdd(items []entities.ImdbItem) error    { f.ratingsAdded = items; return nil }
+func (f *fakeTrakt) RatingsRemove(items []entities.ImdbItem) error { f.ratingsRemoved = items; return nil }
+func (f *fakeTrakt) WatchlistItemsAdd(items []entities.ImdbItem) error {
+	f.watchlistAdded = items
+	return nil
+}
+func (f *fakeTrakt) ListItemsAdd(listId string, items []entities.ImdbItem) error {
+	f.listAdds[listId] = items
+	return nil
+}
+func (f *fakeTrakt) ListItemsRemove(string, []entities.ImdbItem) error { return nil }
+
+func newTestWorker(imdb *fakeImdb, trakt *fakeTrakt) *Worker {
+	return NewWorker(nil, imdb, trakt, zap.NewNop())
+}
+
+func TestExecute_KindRatings_PushesAddedAndRemoved(t *testing.T) {
+	imdb := &fakeImdb{ratingsDelta: &store.Delta{
+		Added:   []entities.ImdbItem{{Id: "tt1"}},
+		Removed: []entities.ImdbItem{{Id: "tt2"}},
+	}}
+	trakt := newFakeTrakt()
+	w := newTestWorker(imdb, trakt)
+	if err := w.execute(&SyncJob{Kind: KindRatings}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trakt.ratingsAdded) != 1 || trakt.ratingsAdded[0].Id != "tt1" {
+		t.Fatalf("expected tt1 added, got %+v", trakt.ratingsAdded)
+	}
+	if len(trakt.ratingsRemoved) != 1 || trakt.ratingsRemoved[0].Id != "tt2" {
+		t.Fatalf("expected tt2 removed, got %+v", trakt.ratingsRemoved)
+	}
+}
+
+func TestExecute_KindRatings_SkipsEmptyDeltaSides(t *testing.T) {
+	imdb := &fakeImdb{ratingsDelta: &store.Delta{}}
+	trakt := newFakeTrakt()
+	w := newTestWorker(imdb, trakt)
+	if err := w.execute(&SyncJob{Kind: KindRatings}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if trakt.ratingsAdded != nil || trakt.ratingsRemoved != nil {
+		t.Fatalf("expected no trakt calls for an empty delta, got added=%+v removed=%+v", trakt.ratingsAdded, trakt.ratingsRemoved)
+	}
+}
+
+func TestExecute_KindList_UsesListDeltaFrom(t *testing.T) {
+	list := &entities.ImdbList{ListId: "ls1", ListItems: []entities.ImdbItem{{Id: "tt1"}}}
+	imdb := &fakeImdb{list: list, listDelta: &store.Delta{Added: list.ListItems}}
+	trakt := newFakeTrakt()
+	w := newTestWorker(imdb, trakt)
+	if err := w.execute(&SyncJob{Kind: KindList, ListId: "ls1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(trakt.listAdds["ls1"]) != 1 {
+		t.Fatalf("expected list ls1 to receive its added item, got %+v", trakt.listAdds)
+	}
+}
+
+func TestExecute_KindFull_SyncsSuccessfulListsDespitePartialError(t *testing.T) {
+	lists := []entities.ImdbList{{ListId: "ls1", ListItems: []entities.ImdbItem{{Id: "tt1"}}}}
+	imdb := &fakeImdb{
+		ratingsDelta: &store.Delta{},
+		lists:        lists,
+		listsErr:     errors.New("list ls2: not found"),
+		listDelta:    &store.Delta{Added: []entities.ImdbItem{{Id: "tt1"}}},
+	}
+	trakt := newFakeTrakt()
+	w := newTestWorker(imdb, trakt)
+	err := w.execute(&SyncJob{Kind: KindFull})
+	if err == nil {
+		t.Fatalf("expected the aggregate ListsGetAll error to propagate")
+	}
+	if len(trakt.listAdds["ls1"]) != 1 {
+		t.Fatalf("expected ls1 to still be synced despite the aggregate error, got %+v", trakt.listAdds)
+	}
+}
+
+func TestExecute_UnknownKind_ReturnsError(t *testing.T) {
+	w := newTestWorker(&fakeImdb{}, newFakeTrakt())
+	if err := w.execute(&SyncJob{Kind: Kind("bogus")}); err == nil {
+		t.Fatalf("expected an error for an unknown job kind")
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want bool
+	}{
+		"plain error":   {errors.New("boom"), true},
+		"5xx api error": {&client.ApiError{StatusCode: 500}, true},
+		"429 api error": {&client.ApiError{StatusCode: 429}, true},
+		"4xx api error": {&client.ApiError{StatusCode: 404}, false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := isRetryable(tc.err); got != tc.want {
+				t.Fatalf("isRetryable() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}